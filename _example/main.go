@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"embed"
 	"errors"
 	"html/template"
@@ -36,8 +37,9 @@ func main() {
 	})
 
 	var (
-		staticFS http.FileSystem
-		t        func() *template.Template
+		staticFS    http.FileSystem
+		templatesFS fs.FS
+		t           func() *template.Template
 	)
 	if dev {
 		root, err := os.OpenRoot("_example")
@@ -48,14 +50,29 @@ func main() {
 		if err != nil {
 			panic(err)
 		}
+		templatesFS, err = fs.Sub(root.FS(), "web/templates")
+		if err != nil {
+			panic(err)
+		}
 
 		staticFS = http.FS(subFS)
 		t = func() *template.Template {
-			return reloader.MustParseTemplate(template.Must(template.New("templates").
-				ParseFS(root.FS(), "web/templates/*.gohtml")),
-			)
+			tmpl, err := template.New("templates").ParseFS(root.FS(), "web/templates/*.gohtml")
+			if err == nil {
+				tmpl, err = reloader.ParseTemplate(tmpl)
+			}
+			// Bad edits shouldn't take the dev server down: on error this
+			// keeps serving the last template that parsed successfully and
+			// pushes an overlay to any open pages.
+			return reloader.MustParseTemplateSafe(tmpl, err)
 		}
 
+		// Serve .scss files as plain CSS so the example doesn't need a real
+		// Sass toolchain; a production app would shell out to a compiler here.
+		reloader.AddPipeline("*.scss", func(_ string, in []byte) ([]byte, string, error) {
+			return in, "text/css; charset=utf-8", nil
+		})
+
 		reloader.Start(subFS)
 		defer reloader.Close()
 	} else {
@@ -63,6 +80,10 @@ func main() {
 		if err != nil {
 			panic(err)
 		}
+		templatesFS, err = fs.Sub(templates, "web/templates")
+		if err != nil {
+			panic(err)
+		}
 
 		staticFS = http.FS(subStaticFS)
 		st := reloader.MustParseTemplate(template.Must(template.New("templates").
@@ -75,10 +96,19 @@ func main() {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
-		if err := t().ExecuteTemplate(w, "index.gohtml", nil); err != nil {
+		// ExecuteTemplate writes incrementally, so a mid-render error would
+		// otherwise leave a half-written response; render into a buffer
+		// first so we can show the error overlay instead.
+		var buf bytes.Buffer
+		if err := t().ExecuteTemplate(&buf, "index.gohtml", nil); err != nil {
 			slog.Error("Failed to render index template", slog.String("error", err.Error()))
+			reloader.RenderError(w, templatesFS, err)
 			return
 		}
+
+		if _, err := buf.WriteTo(w); err != nil {
+			slog.Error("Failed to write index template", slog.String("error", err.Error()))
+		}
 	})
 
 	mux.Handle(reloadRoute, reloader.Handler())
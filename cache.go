@@ -3,9 +3,13 @@ package goreload
 import (
 	"fmt"
 	"net/http"
+	"path"
+	"strings"
 )
 
 // CacheMiddleware is a middleware that sets Cache-Control headers to enable caching for the specified max age.
+// When the request path matches a pattern registered with AddPipeline, it's compiled on the fly by the matching
+// transform instead of being served as-is.
 func (r *Reloader) CacheMiddleware(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, rq *http.Request) {
 		if !r.Enabled() {
@@ -13,6 +17,13 @@ func (r *Reloader) CacheMiddleware(handler http.Handler) http.Handler {
 			return
 		}
 
+		name := strings.TrimPrefix(path.Clean(rq.URL.Path), "/")
+		if transform, root, ok := r.matchPipeline(name); ok {
+			if r.servePipeline(w, rq, transform, root) {
+				return
+			}
+		}
+
 		w.Header().Set("Cache-Control", fmt.Sprintf("stale-while-revalidate, max-age=%d", r.maxAge))
 		handler.ServeHTTP(w, rq)
 	})
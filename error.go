@@ -0,0 +1,134 @@
+package goreload
+
+import (
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// snippetContext is the number of lines shown before and after the failing
+// line in a rendered error overlay.
+const snippetContext = 5
+
+// templateErrorLocation matches the "template: file:line: message" and
+// "template: file:line:col: message" formats used by html/template's parse
+// and execution errors.
+var templateErrorLocation = regexp.MustCompile(`^template: ([^:]+):(\d+)(?::\d+)?: (.*)$`)
+
+// templateErrorData is the data passed to templates/error.gohtml.
+type templateErrorData struct {
+	Message string
+	File    string
+	Line    int
+	Snippet []errorSnippetLine
+}
+
+// errorSnippetLine is a single line of source shown in the overlay.
+type errorSnippetLine struct {
+	Number    int
+	Text      string
+	Highlight bool
+}
+
+// RenderError writes a styled HTML error page describing err, including the
+// offending file and a snippet of its source when err can be traced back to
+// a template file and line, plus the live-reload client script so fixing the
+// file auto-reloads the page. It's meant to be called from request handlers
+// whenever ParseFS or ExecuteTemplate returns an error, in place of a panic
+// or a half-written response.
+//
+// templateRoot is the fs.FS the failing templates were parsed from (e.g. the
+// same one passed to ParseFS), used to read the snippet: html/template
+// errors only ever name a template's base file name, never its path within
+// templateRoot, so templateRoot must be scoped to the directory the
+// templates actually live in for the snippet to be found. Pass a nil
+// templateRoot to skip the snippet.
+func (r *Reloader) RenderError(w http.ResponseWriter, templateRoot fs.FS, err error) {
+	data := newTemplateErrorData(templateRoot, err)
+
+	t, parseErr := r.ParseTemplate(template.New("error"))
+	if parseErr == nil {
+		t, parseErr = t.ParseFS(reloadTemplates, "templates/error.gohtml")
+	}
+	if parseErr != nil {
+		r.logger.Error("failed to render error overlay", slog.Any("err", parseErr))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+
+	if execErr := t.ExecuteTemplate(w, "error.gohtml", data); execErr != nil {
+		r.logger.Error("failed to write error overlay", slog.Any("err", execErr))
+	}
+}
+
+func newTemplateErrorData(templateRoot fs.FS, err error) templateErrorData {
+	file, line := parseTemplateErrorLocation(err)
+
+	data := templateErrorData{
+		Message: err.Error(),
+		File:    file,
+		Line:    line,
+	}
+
+	if templateRoot != nil && file != "" && line > 0 {
+		data.Snippet = readErrorSnippet(templateRoot, file, line)
+	}
+
+	return data
+}
+
+// parseTemplateErrorLocation extracts the file and line from an html/template
+// parse or execution error, returning ("", 0) if err doesn't match the
+// expected format.
+func parseTemplateErrorLocation(err error) (file string, line int) {
+	m := templateErrorLocation.FindStringSubmatch(err.Error())
+	if m == nil {
+		return "", 0
+	}
+
+	line, convErr := strconv.Atoi(m[2])
+	if convErr != nil {
+		return "", 0
+	}
+
+	return m[1], line
+}
+
+// readErrorSnippet reads file from root and returns the lines around line,
+// with line itself marked as the highlighted one. It returns nil if file
+// can't be read.
+func readErrorSnippet(root fs.FS, file string, line int) []errorSnippetLine {
+	content, err := fs.ReadFile(root, file)
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	start := line - snippetContext
+	if start < 1 {
+		start = 1
+	}
+	end := line + snippetContext
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	snippet := make([]errorSnippetLine, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		snippet = append(snippet, errorSnippetLine{
+			Number:    n,
+			Text:      lines[n-1],
+			Highlight: n == line,
+		})
+	}
+
+	return snippet
+}
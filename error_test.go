@@ -0,0 +1,44 @@
+package goreload
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTemplateErrorLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantFile string
+		wantLine int
+	}{
+		{
+			name:     "parse error",
+			err:      errors.New(`template: bad.gohtml:3: function "nope" not defined`),
+			wantFile: "bad.gohtml",
+			wantLine: 3,
+		},
+		{
+			name:     "execution error with column",
+			err:      errors.New(`template: bad.gohtml:12:7: executing "bad.gohtml" at <.Nope>: nil pointer evaluating`),
+			wantFile: "bad.gohtml",
+			wantLine: 12,
+		},
+		{
+			name:     "unrelated error",
+			err:      errors.New("open foo: no such file or directory"),
+			wantFile: "",
+			wantLine: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, line := parseTemplateErrorLocation(tt.err)
+			if file != tt.wantFile || line != tt.wantLine {
+				t.Errorf("parseTemplateErrorLocation(%q) = (%q, %d), want (%q, %d)",
+					tt.err, file, line, tt.wantFile, tt.wantLine)
+			}
+		})
+	}
+}
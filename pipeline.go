@@ -0,0 +1,103 @@
+package goreload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// PipelineFunc transforms the contents of an asset before it's served, e.g.
+// compiling Sass to CSS or TypeScript to JavaScript. path is the request
+// path relative to the fs.FS passed to Start; in is its current contents.
+// The returned contentType, when non-empty, is set as the response's
+// Content-Type.
+type PipelineFunc func(path string, in []byte) (out []byte, contentType string, err error)
+
+// pipelineEntry pairs a glob pattern with the transform it selects.
+type pipelineEntry struct {
+	pattern   string
+	transform PipelineFunc
+}
+
+// AddPipeline registers transform to run on request for any asset whose file
+// name (the base of its path relative to the fs.FS passed to Start) matches
+// pattern, as interpreted by path.Match, e.g. "*.scss" or "*.ts". Patterns
+// only ever match a file name, never directory components, so assets nested
+// under subdirectories are matched the same way as ones at the root. Matching
+// requests are compiled on the fly by CacheMiddleware instead of being served
+// as-is, with the transform's output cached behind a strong ETag so
+// unchanged output reuses the browser cache. Patterns are tried in
+// registration order; the first match wins.
+func (r *Reloader) AddPipeline(pattern string, transform PipelineFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pipelines = append(r.pipelines, pipelineEntry{pattern: pattern, transform: transform})
+}
+
+// matchPipeline returns the transform registered for name, if any.
+func (r *Reloader) matchPipeline(name string) (PipelineFunc, fs.FS, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	base := path.Base(name)
+	for _, p := range r.pipelines {
+		if ok, _ := path.Match(p.pattern, base); ok {
+			return p.transform, r.assetRoot, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// servePipeline runs the registered transform for rq's path and writes its
+// output, setting a strong ETag derived from the transformed result and a
+// Last-Modified taken from the source file's mtime (when root can report
+// one), and honouring conditional requests. It reports whether it handled
+// the request at all, so CacheMiddleware can fall back to the wrapped
+// handler when there is no root to read assets from.
+func (r *Reloader) servePipeline(w http.ResponseWriter, rq *http.Request, transform PipelineFunc, root fs.FS) bool {
+	if root == nil {
+		return false
+	}
+
+	name := strings.TrimPrefix(path.Clean(rq.URL.Path), "/")
+
+	in, err := fs.ReadFile(root, name)
+	if err != nil {
+		return false
+	}
+
+	out, contentType, err := transform(name, in)
+	if err != nil {
+		r.logger.Error("asset pipeline transform failed", slog.String("path", name), slog.Any("err", err))
+		http.Error(w, "asset pipeline error", http.StatusInternalServerError)
+		return true
+	}
+
+	sum := sha256.Sum256(out)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if info, statErr := fs.Stat(root, name); statErr == nil {
+		w.Header().Set("Last-Modified", info.ModTime().UTC().Format(http.TimeFormat))
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	if rq.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	if _, err = w.Write(out); err != nil {
+		r.logger.Error("asset pipeline failed to write response", slog.String("path", name), slog.Any("err", err))
+	}
+
+	return true
+}
@@ -0,0 +1,40 @@
+package goreload
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestMatchPipelineNested(t *testing.T) {
+	r := New(Config{})
+	r.assetRoot = testFS{}
+
+	r.AddPipeline("*.scss", func(_ string, in []byte) ([]byte, string, error) {
+		return in, "text/css", nil
+	})
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"root file", "app.scss", true},
+		{"nested file", "static/css/app.scss", true},
+		{"non-matching extension", "static/css/app.css", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok := r.matchPipeline(tt.path)
+			if ok != tt.want {
+				t.Errorf("matchPipeline(%q) ok = %v, want %v", tt.path, ok, tt.want)
+			}
+		})
+	}
+}
+
+// testFS is a minimal non-nil fs.FS standing in for the asset root that
+// Start would set, without needing a real filesystem.
+type testFS struct{}
+
+func (testFS) Open(string) (fs.File, error) { return nil, fs.ErrNotExist }
@@ -3,12 +3,19 @@ package goreload
 import (
 	"context"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log/slog"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
 )
 
 //go:embed templates/*.gohtml
@@ -23,19 +30,43 @@ type Config struct {
 	Enabled bool
 	// MaxAge is the maximum age for cached assets when live reload is enabled.
 	MaxAge time.Duration
+	// WatchMode selects the dev watcher strategy. Defaults to WatchModeAuto.
+	WatchMode WatchMode
+	// DebounceInterval controls how long the fsnotify watcher waits for
+	// further events before notifying subscribers. Defaults to 75ms.
+	DebounceInterval time.Duration
+	// Transport selects which client script ParseTemplate embeds: an
+	// EventSource talking to Handler, or a WebSocket talking to
+	// WebSocketHandler. Defaults to TransportSSE.
+	Transport Transport
 }
 
 // New creates a new Reloader with the provided configuration.
 func New(config Config) *Reloader {
 	return &Reloader{
-		logger:  config.Logger,
-		clients: make(map[int]chan struct{}),
-		route:   config.Route,
-		enabled: config.Enabled,
-		maxAge:  int(config.MaxAge.Seconds()),
+		logger:           config.Logger,
+		clients:          make(map[int]chan reloadEvent),
+		route:            config.Route,
+		enabled:          config.Enabled,
+		maxAge:           int(config.MaxAge.Seconds()),
+		watchMode:        config.WatchMode,
+		debounceInterval: config.DebounceInterval,
+		transport:        config.Transport,
 	}
 }
 
+// Transport selects the wire protocol the embedded client script speaks.
+type Transport string
+
+const (
+	// TransportSSE talks to Handler over Server-Sent Events. This is the
+	// default.
+	TransportSSE Transport = "sse"
+	// TransportWebSocket talks to WebSocketHandler using the LiveReload v7
+	// protocol.
+	TransportWebSocket Transport = "websocket"
+)
+
 // Reloader implements a live reload notifier that broadcasts reload signals to
 // subscribed clients.
 type Reloader struct {
@@ -43,18 +74,45 @@ type Reloader struct {
 	mu          sync.Mutex
 	closed      bool
 	nextID      int
-	clients     map[int]chan struct{}
+	clients     map[int]chan reloadEvent
 	route       string
 	enabled     bool
 	maxAge      int
 	watchCancel context.CancelFunc
+
+	watchMode        WatchMode
+	debounceInterval time.Duration
+	transport        Transport
+
+	lastTemplate *template.Template
+
+	// assetRoot is the fs.FS passed to Start, used by the asset pipeline to
+	// read source files for on-request transforms.
+	assetRoot fs.FS
+	pipelines []pipelineEntry
+}
+
+// reloadEvent describes a single notification pushed to subscribers.
+type reloadEvent struct {
+	// path is the file that changed, as the fsnotify watcher reports it: the
+	// watched directory's on-disk path joined with the file's path under it,
+	// not a path relative to that directory. It is empty when the origin of the change is unknown, e.g. for the polling
+	// watcher or a manual Notify call.
+	path string
+	// liveCSS indicates path is a stylesheet that can be hot-swapped in
+	// place instead of triggering a full page reload.
+	liveCSS bool
+	// isError marks this as a template error notification rather than a
+	// reload; message then carries the error text.
+	isError bool
+	message string
 }
 
 // subscribe registers a new listener and returns a cancellation function along
 // with the channel that delivers reload signals. Callers must invoke the
 // returned function once they are done listening so the notifier can reclaim
 // resources. If the notifier has already been closed we return a nil channel.
-func (r *Reloader) subscribe() (func(), <-chan struct{}) {
+func (r *Reloader) subscribe() (func(), <-chan reloadEvent) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -65,7 +123,7 @@ func (r *Reloader) subscribe() (func(), <-chan struct{}) {
 	id := r.nextID
 	r.nextID++
 
-	ch := make(chan struct{}, 1)
+	ch := make(chan reloadEvent, 1)
 	r.clients[id] = ch
 
 	var once sync.Once
@@ -89,6 +147,36 @@ func (r *Reloader) subscribe() (func(), <-chan struct{}) {
 // on slow readers. If a listener already has a pending notification we leave it
 // untouched so it still reloads on its next poll.
 func (r *Reloader) Notify() {
+	r.notify(reloadEvent{})
+}
+
+// NotifyPath is like Notify but records which file triggered the reload, so
+// WebSocketHandler can report it to the client. If path is a stylesheet, the
+// event is marked liveCSS so the client hot-swaps the matching
+// <link rel="stylesheet"> in place instead of reloading the whole page.
+func (r *Reloader) NotifyPath(path string) {
+	r.notify(reloadEvent{path: path, liveCSS: isStylesheet(path)})
+}
+
+// isStylesheet reports whether path looks like a stylesheet that the client
+// can hot-swap instead of reloading the page.
+func isStylesheet(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".css", ".scss", ".sass", ".less":
+		return true
+	default:
+		return false
+	}
+}
+
+// NotifyError pushes an error event to every active listener instead of a
+// reload, so currently-open pages can swap in an error overlay rather than
+// waiting for the next navigation. See MustParseTemplateSafe.
+func (r *Reloader) NotifyError(err error) {
+	r.notify(reloadEvent{isError: true, message: err.Error()})
+}
+
+func (r *Reloader) notify(evt reloadEvent) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -98,7 +186,7 @@ func (r *Reloader) Notify() {
 
 	for _, ch := range r.clients {
 		select {
-		case ch <- struct{}{}:
+		case ch <- evt:
 		default:
 			// channel already has pending notification; skip
 		}
@@ -153,6 +241,12 @@ func (r *Reloader) ParseTemplate(t *template.Template) (*template.Template, erro
 		"LiveReloadRoute": func() string {
 			return r.route
 		},
+		"LiveReloadTransport": func() string {
+			if r.transport == "" {
+				return string(TransportSSE)
+			}
+			return string(r.transport)
+		},
 	}).ParseFS(reloadTemplates, "templates/reload.gohtml")
 }
 
@@ -161,6 +255,34 @@ func (r *Reloader) MustParseTemplate(t *template.Template) *template.Template {
 	return template.Must(r.ParseTemplate(t))
 }
 
+// MustParseTemplateSafe is a drop-in replacement for template.Must(t, err)
+// around a caller's own template parsing: instead of panicking on err, it
+// notifies subscribers with the error via NotifyError, so that currently-open
+// pages display an overlay, and returns the last successfully parsed
+// template so the dev server stays up across bad edits. If no template has
+// ever parsed successfully it returns an empty template.
+func (r *Reloader) MustParseTemplateSafe(t *template.Template, err error) *template.Template {
+	if err != nil {
+		r.logger.Error("failed to parse templates", slog.Any("err", err))
+		r.NotifyError(err)
+
+		r.mu.Lock()
+		last := r.lastTemplate
+		r.mu.Unlock()
+
+		if last != nil {
+			return last
+		}
+		return template.New("empty")
+	}
+
+	r.mu.Lock()
+	r.lastTemplate = t
+	r.mu.Unlock()
+
+	return t
+}
+
 // Handler streams server-sent events that instruct the browser to refresh
 // whenever the dev watcher picks up a change on disk. The SSE connection stays
 // open until the client disconnects or the server shuts down.
@@ -197,11 +319,25 @@ func (r *Reloader) Handler() http.Handler {
 			select {
 			case <-rq.Context().Done():
 				return
-			case _, ok := <-ch:
+			case evt, ok := <-ch:
 				if !ok {
 					return
 				}
-				if _, err := fmt.Fprint(w, "data: reload\n\n"); err != nil {
+
+				var (
+					b   []byte
+					err error
+				)
+				if evt.isError {
+					b, err = json.Marshal(livereloadError{Command: "error", Message: evt.message})
+				} else {
+					b, err = json.Marshal(livereloadReload{Command: "reload", Path: evt.path, LiveCSS: evt.liveCSS})
+				}
+				if err != nil {
+					return
+				}
+
+				if _, err = fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
 					return
 				}
 				flusher.Flush()
@@ -209,3 +345,72 @@ func (r *Reloader) Handler() http.Handler {
 		}
 	})
 }
+
+// WebSocketHandler speaks the LiveReload v7 protocol used by lrserver and
+// livereload.js: after the initial hello handshake, every Notify/NotifyPath
+// call is pushed to the client as a reload command, carrying the changed
+// path when NotifyPath supplied one (Notify sends an empty path), and every
+// NotifyError call as an error command. This lets livereload-aware browser extensions and editor plugins
+// point at goreload without modification.
+func (r *Reloader) WebSocketHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, rq *http.Request) {
+		conn, err := websocket.Accept(w, rq, nil)
+		if err != nil {
+			r.logger.Error("dev reload websocket accept failed", slog.Any("err", err))
+			return
+		}
+		defer conn.CloseNow()
+
+		ctx := rq.Context()
+
+		cancel, ch := r.subscribe()
+		if ch == nil {
+			_ = conn.Close(websocket.StatusGoingAway, "reloader closed")
+			return
+		}
+		defer cancel()
+
+		// The client may send messages (e.g. the protocol's own hello or a
+		// "url" info message); we don't act on them but must keep reading so
+		// the connection's control frames are handled.
+		go func() {
+			for {
+				if _, _, err := conn.Read(ctx); err != nil {
+					return
+				}
+			}
+		}()
+
+		if err = wsjson.Write(ctx, conn, livereloadHello{
+			Command:    "hello",
+			Protocols:  []string{livereloadProtocol},
+			ServerName: "goreload",
+		}); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				if evt.isError {
+					err = wsjson.Write(ctx, conn, livereloadError{Command: "error", Message: evt.message})
+				} else {
+					err = wsjson.Write(ctx, conn, livereloadReload{
+						Command: "reload",
+						Path:    evt.path,
+						LiveCSS: evt.liveCSS,
+					})
+				}
+				if err != nil {
+					return
+				}
+			}
+		}
+	})
+}
@@ -7,51 +7,241 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchMode selects the strategy the dev watcher uses to detect changes.
+type WatchMode string
+
+const (
+	// WatchModeAuto uses fsnotify when the watched fs.FS is backed by a real
+	// directory on disk and falls back to polling otherwise. This is the
+	// default.
+	WatchModeAuto WatchMode = "auto"
+	// WatchModeFSNotify forces the fsnotify-backed watcher. Start logs a
+	// warning and falls back to polling if the fs.FS can't be resolved to a
+	// real directory.
+	WatchModeFSNotify WatchMode = "fsnotify"
+	// WatchModePoll forces the polling fingerprint watcher regardless of the
+	// underlying fs.FS.
+	WatchModePoll WatchMode = "poll"
 )
 
-// devWatcherInterval controls how frequently the dev watcher checks for changes.
+// devWatcherInterval controls how frequently the polling fallback checks for
+// changes.
 const devWatcherInterval = 500 * time.Millisecond
 
-// Start begins polling the on-disk copy of server/web for changes.
-// Any time the directory fingerprint flips we notify all reload subscribers via
-// the provided notifier. The returned cancel function stops the watcher.
+// defaultDebounceInterval is used when Config.DebounceInterval is unset.
+const defaultDebounceInterval = 75 * time.Millisecond
+
+// Start begins watching dir for changes. When dir is backed by a real
+// directory on disk (e.g. os.DirFS or an os.Root's FS) and WatchMode allows
+// it, changes are detected via fsnotify with events coalesced over the
+// configured debounce interval. Otherwise Start falls back to polling a
+// directory fingerprint, which also covers fs.FS implementations that aren't
+// backed by disk, such as embed.FS. Any time a change is detected we notify
+// all reload subscribers. Close stops the watcher.
 func (r *Reloader) Start(dir fs.FS) {
 	ctx, cancel := context.WithCancel(context.Background())
 	r.watchCancel = cancel
 
-	go func() {
-		// Ensure there is a final notification when the watcher stops so any open
-		// SSE connections can exit rather than hanging indefinitely.
-		defer r.Notify()
+	r.mu.Lock()
+	r.assetRoot = dir
+	r.mu.Unlock()
 
-		lastFingerprint, err := directoryFingerprint(dir)
-		if err != nil {
-			r.logger.Error("dev reload watcher failed to read directory", slog.Any("err", err))
+	root, isRealDir := realDir(dir)
+
+	mode := r.watchMode
+	if mode == "" {
+		mode = WatchModeAuto
+	}
+
+	switch mode {
+	case WatchModeFSNotify:
+		if !isRealDir {
+			r.logger.Warn("fsnotify watch mode requested but the watched fs.FS is not backed by a real directory; falling back to polling")
+			go r.pollLoop(ctx, dir)
+			return
+		}
+		go r.fsnotifyLoop(ctx, root)
+	case WatchModePoll:
+		go r.pollLoop(ctx, dir)
+	default:
+		if isRealDir {
+			go r.fsnotifyLoop(ctx, root)
+		} else {
+			go r.pollLoop(ctx, dir)
 		}
+	}
+}
+
+// realDir returns the real on-disk path backing dir, if any. It works by
+// opening the filesystem root and checking whether the returned file is an
+// *os.File, which is true for os.DirFS and an os.Root's FS, but not for
+// in-memory filesystems such as embed.FS or fstest.MapFS.
+func realDir(dir fs.FS) (string, bool) {
+	f, err := dir.Open(".")
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	osFile, ok := f.(*os.File)
+	if !ok {
+		return "", false
+	}
+
+	info, err := osFile.Stat()
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+
+	return osFile.Name(), true
+}
+
+// pollLoop polls dir for changes by periodically
+// recomputing its fingerprint. It is used whenever dir can't be watched with
+// fsnotify, and as the watch mode of last resort.
+func (r *Reloader) pollLoop(ctx context.Context, dir fs.FS) {
+	// Ensure there is a final notification when the watcher stops so any open
+	// SSE/WebSocket connections can exit rather than hanging indefinitely.
+	defer r.Notify()
+
+	lastFingerprint, err := directoryFingerprint(dir)
+	if err != nil {
+		r.logger.Error("dev reload watcher failed to read directory", slog.Any("err", err))
+	}
 
-		ticker := time.NewTicker(devWatcherInterval)
-		defer ticker.Stop()
+	ticker := time.NewTicker(devWatcherInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fp, err := directoryFingerprint(dir)
+			if err != nil {
+				r.logger.Error("dev reload watcher failed to scan directory", slog.Any("err", err))
+				continue
+			}
+
+			if fp != lastFingerprint {
+				lastFingerprint = fp
+				// Directory changed; broadcast to all listeners.
+				r.Notify()
+			}
+		}
+	}
+}
 
-		for {
-			select {
-			case <-ctx.Done():
+// fsnotifyLoop watches root and its subdirectories for changes, coalescing
+// bursts of events over the configured debounce interval before notifying
+// subscribers.
+func (r *Reloader) fsnotifyLoop(ctx context.Context, root string) {
+	defer r.Notify()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		r.logger.Error("dev reload watcher failed to start fsnotify", slog.Any("err", err))
+		return
+	}
+	defer watcher.Close()
+
+	if err = addRecursive(watcher, root); err != nil {
+		r.logger.Error("dev reload watcher failed to watch directory", slog.String("path", root), slog.Any("err", err))
+	}
+
+	debounce := r.debounceInterval
+	if debounce <= 0 {
+		debounce = defaultDebounceInterval
+	}
+
+	timer := time.NewTimer(debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	// changed collects every distinct path that changed during the debounce
+	// window (as a set, so repeated writes to the same file only count
+	// once). If any of them isn't a stylesheet, sawOther fires a single
+	// pathless Notify (full reload) once the timer fires; otherwise every
+	// distinct stylesheet gets its own NotifyPath, sent back to back. notify
+	// drops an event for a subscriber that hasn't yet read the previous one,
+	// so when several stylesheets change in one window a subscriber may
+	// receive only some of them and the other <link>s are not hot-swapped.
+	var (
+		changed  = map[string]struct{}{}
+		sawOther bool
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
 				return
-			case <-ticker.C:
-				fp, err := directoryFingerprint(dir)
-				if err != nil {
-					r.logger.Error("dev reload watcher failed to scan directory", slog.Any("err", err))
-					continue
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err = addRecursive(watcher, event.Name); err != nil {
+						r.logger.Error("dev reload watcher failed to watch new directory", slog.String("path", event.Name), slog.Any("err", err))
+					}
+				}
+			}
+
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				changed[event.Name] = struct{}{}
+				if !isStylesheet(event.Name) {
+					sawOther = true
 				}
 
-				if fp != lastFingerprint {
-					lastFingerprint = fp
-					// Directory changed; broadcast to all listeners.
-					r.Notify()
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
 				}
+				timer.Reset(debounce)
 			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Error("dev reload watcher error", slog.Any("err", err))
+		case <-timer.C:
+			if sawOther {
+				r.Notify()
+			} else {
+				for path := range changed {
+					r.NotifyPath(path)
+				}
+			}
+			changed, sawOther = map[string]struct{}{}, false
+		}
+	}
+}
+
+// addRecursive registers root and all of its subdirectories with watcher.
+func addRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-	}()
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		return watcher.Add(path)
+	})
 }
 
 // directoryFingerprint produces a deterministic hash for the current state of
@@ -0,0 +1,25 @@
+package goreload
+
+import "testing"
+
+func TestIsStylesheet(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"app.css", true},
+		{"app.scss", true},
+		{"app.sass", true},
+		{"app.less", true},
+		{"static/css/app.CSS", true},
+		{"app.js", false},
+		{"index.gohtml", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isStylesheet(tt.path); got != tt.want {
+			t.Errorf("isStylesheet(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package goreload
+
+// livereloadProtocol identifies the LiveReload wire protocol version
+// implemented by WebSocketHandler, as advertised in the hello handshake.
+const livereloadProtocol = "http://livereload.com/protocols/official-7"
+
+// livereloadHello is the handshake message sent once a WebSocketHandler
+// connection is accepted.
+type livereloadHello struct {
+	Command    string   `json:"command"`
+	Protocols  []string `json:"protocols"`
+	ServerName string   `json:"serverName"`
+}
+
+// livereloadReload is sent every time the watcher detects a change. It's
+// shared by Handler and WebSocketHandler, which only differ in how they
+// frame it on the wire (SSE "data:" lines vs. WebSocket text frames).
+type livereloadReload struct {
+	Command string `json:"command"`
+	Path    string `json:"path"`
+	LiveCSS bool   `json:"liveCSS"`
+}
+
+// livereloadError is a goreload extension to the protocol, sent when a
+// template fails to parse or render so open pages can show an overlay
+// instead of reloading into a broken page. Like livereloadReload it's shared
+// by Handler and WebSocketHandler.
+type livereloadError struct {
+	Command string `json:"command"`
+	Message string `json:"message"`
+}